@@ -1,11 +1,9 @@
 package main
 
 import (
-	"crypto/rand"
-	"crypto/sha256"
-	"crypto/subtle"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"log/slog"
 	"net/http"
 	"time"
@@ -13,12 +11,12 @@ import (
 	"github.com/Suryarpan/chat-api/internal/apiconf"
 	"github.com/Suryarpan/chat-api/internal/auth"
 	"github.com/Suryarpan/chat-api/internal/database"
+	"github.com/Suryarpan/chat-api/internal/ratelimit"
 	"github.com/Suryarpan/chat-api/render"
 	"github.com/go-chi/chi/v5"
 	"github.com/go-playground/validator/v10"
 	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgtype"
-	"golang.org/x/crypto/pbkdf2"
 )
 
 const (
@@ -27,10 +25,48 @@ const (
 	tokenGenerationErrorMssg     = "could not login user at this time"
 )
 
-func saltyPassword(password, salt []byte) []byte {
-	iterations := 10_000
-	hashed := pbkdf2.Key(password, salt, iterations, 512, sha256.New)
-	return hashed
+const (
+	// loginMaxFailedAttempts is how many consecutive bad logins a single
+	// username tolerates before it is locked out, independent of the IP
+	// the attempts come from.
+	loginMaxFailedAttempts = 5
+	// loginLockoutWindow is how long a username stays locked after
+	// hitting loginMaxFailedAttempts.
+	loginLockoutWindow = 15 * time.Minute
+)
+
+func loginAttemptKey(username string) string {
+	return "login|" + username
+}
+
+// loginLockedOut reports whether username is currently locked out of
+// login, and if so, how long until the lockout clears.
+func loginLockedOut(r *http.Request, limiter *ratelimit.Limiter, username string) (locked bool, retryAfter time.Duration) {
+	locked, retryAfter, err := limiter.IsOverLimit(r.Context(), loginAttemptKey(username), loginMaxFailedAttempts, loginLockoutWindow)
+	if err != nil {
+		slog.Error("could not check login lockout", "error", err)
+		return false, 0
+	}
+	return locked, retryAfter
+}
+
+// recordLoginFailure records a failed login attempt for username, emitting
+// a structured log once it tips the account into lockout so operators can
+// alert on it.
+func recordLoginFailure(r *http.Request, limiter *ratelimit.Limiter, username string) {
+	locked, retryAfter, err := limiter.RecordAttempt(r.Context(), loginAttemptKey(username), loginMaxFailedAttempts, loginLockoutWindow)
+	if err != nil {
+		slog.Error("could not record login failure", "error", err)
+		return
+	}
+	if locked {
+		slog.Warn("login lockout triggered",
+			"ip", ratelimit.ClientIP(r),
+			"username", username,
+			"route", "/auth/login",
+			"remaining_lockout", retryAfter.String(),
+		)
+	}
 }
 
 type loginUserData struct {
@@ -39,11 +75,91 @@ type loginUserData struct {
 }
 
 type loginResponse struct {
-	Token        string           `json:"token"`
-	TokenType    string           `json:"token_type"`
-	Username     string           `json:"username"`
-	DisplayName  string           `json:"display_name"`
-	LastLoggedIn pgtype.Timestamp `json:"last_logged_in"`
+	AccessToken      string           `json:"access_token"`
+	TokenType        string           `json:"token_type"`
+	RefreshToken     string           `json:"refresh_token"`
+	ExpiresIn        int64            `json:"expires_in"`
+	RefreshExpiresIn int64            `json:"refresh_expires_in"`
+	Username         string           `json:"username"`
+	DisplayName      string           `json:"display_name"`
+	LastLoggedIn     pgtype.Timestamp `json:"last_logged_in"`
+}
+
+type refreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token" validate:"required"`
+}
+
+type refreshResponse struct {
+	AccessToken      string `json:"access_token"`
+	TokenType        string `json:"token_type"`
+	RefreshToken     string `json:"refresh_token"`
+	ExpiresIn        int64  `json:"expires_in"`
+	RefreshExpiresIn int64  `json:"refresh_expires_in"`
+}
+
+// issueSession creates a new refresh token row for user and returns the
+// plaintext refresh token to hand back to the client.
+func issueSession(r *http.Request, queries *database.Queries, user database.User, rotatedFrom pgtype.UUID) (plainRefresh string, err error) {
+	plainRefresh, hash, err := auth.NewRefreshToken()
+	if err != nil {
+		return "", err
+	}
+	now := time.Now().UTC()
+	_, err = queries.CreateRefreshToken(r.Context(), database.CreateRefreshTokenParams{
+		UserPvtID:   user.PvtID,
+		TokenHash:   hash,
+		IssuedAt:    now,
+		ExpiresAt:   now.Add(auth.RefreshTokenTTL),
+		RotatedFrom: rotatedFrom,
+		UserAgent:   pgtype.Text{String: r.UserAgent(), Valid: true},
+		Ip:          pgtype.Text{String: ratelimit.ClientIP(r), Valid: true},
+	})
+	return plainRefresh, err
+}
+
+type mfaRequiredResponse struct {
+	MFARequired bool   `json:"mfa_required"`
+	MFAToken    string `json:"mfa_token"`
+}
+
+type mfaLoginData struct {
+	MFAToken string `json:"mfa_token" validate:"required"`
+	Code     string `json:"code" validate:"required,min=6,max=8,alphanum"`
+}
+
+// finalizeLogin completes a successful authentication (password-only, or
+// password+MFA) by recording the login time and issuing a fresh access
+// and refresh token pair.
+func finalizeLogin(r *http.Request, queries *database.Queries, user database.User) (loginResponse, error) {
+	err := queries.UpdateLoggedInTime(r.Context(), database.UpdateLoggedInTimeParams{
+		LastLoggedIn: pgtype.Timestamp{
+			Time:  time.Now().UTC(),
+			Valid: true,
+		},
+		PvtID: user.PvtID,
+	})
+	if err != nil {
+		return loginResponse{}, err
+	}
+
+	accessToken, err := auth.UserToToken(user)
+	if err != nil {
+		return loginResponse{}, err
+	}
+	refreshToken, err := issueSession(r, queries, user, pgtype.UUID{})
+	if err != nil {
+		return loginResponse{}, err
+	}
+	return loginResponse{
+		AccessToken:      accessToken,
+		TokenType:        auth.TokenPrefix,
+		RefreshToken:     refreshToken,
+		ExpiresIn:        int64(auth.AccessTokenTTL.Seconds()),
+		RefreshExpiresIn: int64(auth.RefreshTokenTTL.Seconds()),
+		Username:         user.Username,
+		DisplayName:      user.DisplayName,
+		LastLoggedIn:     user.LastLoggedIn,
+	}, nil
 }
 
 func handleLogin(w http.ResponseWriter, r *http.Request) {
@@ -67,55 +183,139 @@ func handleLogin(w http.ResponseWriter, r *http.Request) {
 	}
 
 	queries := database.New(apiCfg.ConnPool)
+	limiter := ratelimit.Shared(apiCfg.ConnPool)
+	if locked, retryAfter := loginLockedOut(r, limiter, lu.Username); locked {
+		w.Header().Set("Retry-After", fmt.Sprintf("%d", int64(retryAfter.Seconds())))
+		render.RespondFailure(w, http.StatusForbidden, "too many failed login attempts, try again later")
+		return
+	}
+
 	user, err := queries.GetUserByName(r.Context(), lu.Username)
 	if err != nil {
+		recordLoginFailure(r, limiter, lu.Username)
 		render.RespondFailure(w, http.StatusBadRequest, "username or password is invalid")
 		return
 	}
-	hashedPassword := saltyPassword([]byte(lu.Password), user.PasswordSalt)
-	if subtle.ConstantTimeCompare(hashedPassword, user.Password) != 1 {
+	if user.AuthType != "" && user.AuthType != "local" {
+		render.RespondFailure(w, http.StatusBadRequest, "this account signs in through "+user.AuthType)
+		return
+	}
+	hasher := auth.DefaultHasher()
+	ok, needsRehash, err := auth.VerifyPassword(hasher, user.Password, user.PasswordSalt, []byte(lu.Password))
+	if err != nil {
+		slog.Error("error verifying password", "error", err)
+		render.RespondFailure(w, http.StatusInternalServerError, internalServerErrorMssg)
+		return
+	}
+	if !ok {
+		recordLoginFailure(r, limiter, lu.Username)
 		render.RespondFailure(w, http.StatusBadRequest, "username or password is invalid")
 		return
 	}
+	limiter.Reset(r.Context(), loginAttemptKey(lu.Username))
+	if needsRehash {
+		rehashed, err := hasher.Hash([]byte(lu.Password))
+		if err != nil {
+			slog.Error("error rehashing password", "error", err)
+		} else if _, err := queries.UpdateUserPassword(r.Context(), database.UpdateUserPasswordParams{
+			Password:  []byte(rehashed),
+			UpdatedAt: time.Now().UTC(),
+			PvtID:     user.PvtID,
+		}); err != nil {
+			slog.Error("error persisting rehashed password", "error", err)
+		}
+	}
 
-	err = queries.UpdateLoggedInTime(r.Context(), database.UpdateLoggedInTimeParams{
-		LastLoggedIn: pgtype.Timestamp{
-			Time:  time.Now().UTC(),
-			Valid: true,
-		},
-		PvtID: user.PvtID,
-	})
+	if user.TotpEnabled {
+		render.RespondSuccess(w, http.StatusOK, mfaRequiredResponse{
+			MFARequired: true,
+			MFAToken:    auth.NewMFAToken(user.PvtID),
+		})
+		return
+	}
+
+	resp, err := finalizeLogin(r, queries, user)
+	if err != nil {
+		slog.Error("could not finalize login", "error", err)
+		render.RespondFailure(w, http.StatusInternalServerError, tokenGenerationErrorMssg)
+		return
+	}
+	render.RespondSuccess(w, 200, resp)
+}
+
+func handleLoginMFA(w http.ResponseWriter, r *http.Request) {
+	md := mfaLoginData{}
+	reader := json.NewDecoder(r.Body)
+	reader.Decode(&md)
+
+	apiCfg := apiconf.GetConfig(r)
+	err := apiCfg.Validate.Struct(md)
+	if err != nil {
+		validationErrors, ok := err.(validator.ValidationErrors)
+		if !ok {
+			slog.Error("error with validator definition", "error", err)
+			render.RespondFailure(w, http.StatusInternalServerError, internalServerErrorMssg)
+		} else {
+			render.RespondValidationFailure(w, validationErrors)
+		}
+		return
+	}
+
+	userPvtID, err := auth.ParseMFAToken(md.MFAToken)
 	if err != nil {
-		render.RespondFailure(w, http.StatusInsufficientStorage, tokenGenerationErrorMssg)
+		render.RespondFailure(w, http.StatusUnauthorized, "mfa token is invalid or expired")
+		return
+	}
+
+	queries := database.New(apiCfg.ConnPool)
+	user, err := queries.GetUserByPvtID(r.Context(), userPvtID)
+	if err != nil || !user.TotpEnabled {
+		render.RespondFailure(w, http.StatusUnauthorized, "mfa token is invalid or expired")
+		return
+	}
+
+	if locked, retryAfter := mfaLockedOut(r, queries, user.PvtID); locked {
+		w.Header().Set("Retry-After", fmt.Sprintf("%d", retryAfter))
+		render.RespondFailure(w, http.StatusTooManyRequests, "too many failed mfa attempts, try again later")
+		return
+	}
+
+	ok, err := verifyTOTPOrRecoveryCode(r, queries, user, md.Code)
+	if err != nil {
+		slog.Error("error verifying mfa code", "error", err)
+		render.RespondFailure(w, http.StatusInternalServerError, internalServerErrorMssg)
+		return
+	}
+	if !ok {
+		recordMFAFailure(r, queries, user.PvtID)
+		render.RespondFailure(w, http.StatusUnauthorized, "mfa code is invalid")
+		return
 	}
+	queries.ResetMFALockout(r.Context(), user.PvtID)
 
-	token, err := auth.UserToToken(user)
+	resp, err := finalizeLogin(r, queries, user)
 	if err != nil {
+		slog.Error("could not finalize login", "error", err)
 		render.RespondFailure(w, http.StatusInternalServerError, tokenGenerationErrorMssg)
 		return
 	}
-	render.RespondSuccess(w, 200, loginResponse{
-		Token:        token,
-		TokenType:    auth.TokenPrefix,
-		Username:     user.Username,
-		DisplayName:  user.DisplayName,
-		LastLoggedIn: user.LastLoggedIn,
-	})
+	render.RespondSuccess(w, http.StatusOK, resp)
 }
 
 type registerUserData struct {
 	Username        string `json:"username" validate:"required,min=5,max=50"`
 	DisplayName     string `json:"display_name" validate:"required,min=5,max=150"`
+	Email           string `json:"email" validate:"required,email"`
 	Password        string `json:"password" validate:"required,printascii,min=8,eqfield=ConfirmPassword"`
 	ConfirmPassword string `json:"confirm_password" validate:"required"`
 }
 
-type registerResponse struct {
-	UserId      pgtype.UUID `json:"user_id"`
-	Username    string      `json:"username"`
-	DisplayName string      `json:"display_name"`
-	CreatedAt   time.Time   `json:"created_at"`
-}
+// registrationReceivedMssg is returned for every well-formed registration
+// request, whether or not the username or email was already taken. Giving
+// away that distinction here would let an attacker enumerate accounts; the
+// collision only becomes visible to the requester once they actually hold
+// the mailbox and can complete (or fail to complete) email verification.
+const registrationReceivedMssg = "registration received, check your email to verify your account"
 
 func handleRegister(w http.ResponseWriter, r *http.Request) {
 	ru := registerUserData{}
@@ -135,29 +335,38 @@ func handleRegister(w http.ResponseWriter, r *http.Request) {
 		}
 		return
 	}
-	// check user name with DB
-	queries := database.New(apiCfg.ConnPool)
-	_, err = queries.GetUserByName(r.Context(), ru.Username)
-	if err == nil {
-		render.RespondFailure(w, http.StatusNotAcceptable, map[string]string{"username": "already exists"})
-		return
-	}
-	// generate the password hash
-	passwordSalt := make([]byte, 128)
-	_, err = rand.Read(passwordSalt)
+
+	// Hash before checking for an existing username/email so the two
+	// outcomes take comparable time; skipping the hash on the "already
+	// exists" branch would turn response latency into an enumeration
+	// oracle despite both branches returning the same body.
+	password, err := auth.HashPassword([]byte(ru.Password))
 	if err != nil {
+		slog.Error("error hashing password", "error", err)
 		render.RespondFailure(w, http.StatusInsufficientStorage, insufficientStorageErrorMssg)
 		return
 	}
-	password := saltyPassword([]byte(ru.Password), passwordSalt)
-	// store in DB
+
+	// Run both lookups unconditionally, rather than short-circuiting on
+	// the first hit, so a taken username and a taken email take the same
+	// two round-trips as a free one: the response body already hides
+	// which (if either) collided, and a short-circuit would just move
+	// the same information into response latency instead.
+	queries := database.New(apiCfg.ConnPool)
+	_, nameErr := queries.GetUserByName(r.Context(), ru.Username)
+	_, emailErr := queries.GetUserByEmail(r.Context(), pgtype.Text{String: ru.Email, Valid: true})
+	if nameErr == nil || emailErr == nil {
+		render.RespondSuccess(w, http.StatusAccepted, map[string]string{"status": registrationReceivedMssg})
+		return
+	}
+
 	user, err := queries.CreateUser(r.Context(), database.CreateUserParams{
-		Username:     ru.Username,
-		DisplayName:  ru.DisplayName,
-		Password:     password,
-		PasswordSalt: passwordSalt,
-		CreatedAt:    time.Now().UTC(),
-		UpdatedAt:    time.Now().UTC(),
+		Username:    ru.Username,
+		DisplayName: ru.DisplayName,
+		Email:       pgtype.Text{String: ru.Email, Valid: true},
+		Password:    []byte(password),
+		CreatedAt:   time.Now().UTC(),
+		UpdatedAt:   time.Now().UTC(),
 	})
 	if err != nil {
 		var pgErr *pgconn.PgError
@@ -169,23 +378,187 @@ func handleRegister(w http.ResponseWriter, r *http.Request) {
 				"constraint", pgErr.ConstraintName,
 			)
 		}
-		render.RespondFailure(w, http.StatusInsufficientStorage, insufficientStorageErrorMssg)
+		// A unique-constraint race with a concurrent registration still
+		// looks, from the outside, like any other accepted registration.
+		render.RespondSuccess(w, http.StatusAccepted, map[string]string{"status": registrationReceivedMssg})
 		return
 	}
-	// send back user data
-	render.RespondSuccess(w, http.StatusCreated, registerResponse{
-		UserId:      user.UserID,
-		Username:    user.Username,
-		DisplayName: user.DisplayName,
-		CreatedAt:   user.CreatedAt,
+
+	if err := sendEmailVerification(r, queries, user); err != nil {
+		slog.Error("could not send verification email", "error", err)
+	}
+	render.RespondSuccess(w, http.StatusAccepted, map[string]string{"status": registrationReceivedMssg})
+}
+
+func handleRefresh(w http.ResponseWriter, r *http.Request) {
+	rt := refreshTokenRequest{}
+	reader := json.NewDecoder(r.Body)
+	reader.Decode(&rt)
+
+	apiCfg := apiconf.GetConfig(r)
+	err := apiCfg.Validate.Struct(rt)
+	if err != nil {
+		validationErrors, ok := err.(validator.ValidationErrors)
+		if !ok {
+			slog.Error("error with validator definition", "error", err)
+			render.RespondFailure(w, http.StatusInternalServerError, internalServerErrorMssg)
+		} else {
+			render.RespondValidationFailure(w, validationErrors)
+		}
+		return
+	}
+
+	hash := auth.HashRefreshToken(rt.RefreshToken)
+	queries := database.New(apiCfg.ConnPool)
+	existing, err := queries.GetRefreshTokenByHash(r.Context(), hash)
+	if err != nil {
+		render.RespondFailure(w, http.StatusUnauthorized, "refresh token is invalid")
+		return
+	}
+
+	now := time.Now().UTC()
+	if existing.RevokedAt.Valid {
+		// The same refresh token was presented twice: someone replayed a
+		// token that was already rotated away, so treat the whole chain
+		// as compromised and force the user to log in again.
+		if err := queries.RevokeRefreshTokenChain(r.Context(), database.RevokeRefreshTokenChainParams{
+			RevokedAt: pgtype.Timestamp{Time: now, Valid: true},
+			UserPvtID: existing.UserPvtID,
+		}); err != nil {
+			slog.Error("could not revoke refresh token chain", "error", err)
+		}
+		slog.Warn("refresh token reuse detected", "user_pvt_id", existing.UserPvtID)
+		render.RespondFailure(w, http.StatusUnauthorized, "refresh token has already been used, please log in again")
+		return
+	}
+	if now.After(existing.ExpiresAt) {
+		render.RespondFailure(w, http.StatusUnauthorized, "refresh token has expired")
+		return
+	}
+
+	user, err := queries.GetUserByPvtID(r.Context(), existing.UserPvtID)
+	if err != nil {
+		render.RespondFailure(w, http.StatusUnauthorized, "refresh token is invalid")
+		return
+	}
+
+	tx, err := apiCfg.ConnPool.Begin(r.Context())
+	if err != nil {
+		slog.Error("could not start transaction", "error", err)
+		render.RespondFailure(w, http.StatusInternalServerError, internalServerErrorMssg)
+		return
+	}
+	defer tx.Rollback(r.Context())
+	txQueries := queries.WithTx(tx)
+
+	// Conditioned on revoked_at still being NULL: if a concurrent refresh
+	// already rotated this exact token out from under us, rows affected
+	// is 0 and we treat it the same as replaying an already-rotated
+	// token, rather than silently minting a second new token for the
+	// same presented one.
+	rows, err := txQueries.RevokeRefreshToken(r.Context(), database.RevokeRefreshTokenParams{
+		RevokedAt: pgtype.Timestamp{Time: now, Valid: true},
+		ID:        existing.ID,
+	})
+	if err != nil {
+		slog.Error("could not rotate refresh token", "error", err)
+		render.RespondFailure(w, http.StatusInternalServerError, internalServerErrorMssg)
+		return
+	}
+	if rows == 0 {
+		if err := queries.RevokeRefreshTokenChain(r.Context(), database.RevokeRefreshTokenChainParams{
+			RevokedAt: pgtype.Timestamp{Time: now, Valid: true},
+			UserPvtID: existing.UserPvtID,
+		}); err != nil {
+			slog.Error("could not revoke refresh token chain", "error", err)
+		}
+		slog.Warn("refresh token reuse detected", "user_pvt_id", existing.UserPvtID)
+		render.RespondFailure(w, http.StatusUnauthorized, "refresh token has already been used, please log in again")
+		return
+	}
+	newRefreshToken, err := issueSession(r, txQueries, user, existing.ID)
+	if err != nil {
+		slog.Error("could not issue refresh token", "error", err)
+		render.RespondFailure(w, http.StatusInternalServerError, internalServerErrorMssg)
+		return
+	}
+	if err := tx.Commit(r.Context()); err != nil {
+		slog.Error("could not commit refresh token rotation", "error", err)
+		render.RespondFailure(w, http.StatusInternalServerError, internalServerErrorMssg)
+		return
+	}
+
+	accessToken, err := auth.UserToToken(user)
+	if err != nil {
+		render.RespondFailure(w, http.StatusInternalServerError, tokenGenerationErrorMssg)
+		return
+	}
+	render.RespondSuccess(w, http.StatusOK, refreshResponse{
+		AccessToken:      accessToken,
+		TokenType:        auth.TokenPrefix,
+		RefreshToken:     newRefreshToken,
+		ExpiresIn:        int64(auth.AccessTokenTTL.Seconds()),
+		RefreshExpiresIn: int64(auth.RefreshTokenTTL.Seconds()),
+	})
+}
+
+func handleLogout(w http.ResponseWriter, r *http.Request) {
+	rt := refreshTokenRequest{}
+	reader := json.NewDecoder(r.Body)
+	reader.Decode(&rt)
+
+	apiCfg := apiconf.GetConfig(r)
+	err := apiCfg.Validate.Struct(rt)
+	if err != nil {
+		validationErrors, ok := err.(validator.ValidationErrors)
+		if !ok {
+			slog.Error("error with validator definition", "error", err)
+			render.RespondFailure(w, http.StatusInternalServerError, internalServerErrorMssg)
+		} else {
+			render.RespondValidationFailure(w, validationErrors)
+		}
+		return
+	}
+
+	queries := database.New(apiCfg.ConnPool)
+	existing, err := queries.GetRefreshTokenByHash(r.Context(), auth.HashRefreshToken(rt.RefreshToken))
+	if err != nil {
+		// Already gone; logout is idempotent either way.
+		render.RespondSuccess(w, http.StatusOK, map[string]string{"status": "logged out"})
+		return
+	}
+	err = queries.RevokeRefreshTokenChain(r.Context(), database.RevokeRefreshTokenChainParams{
+		RevokedAt: pgtype.Timestamp{Time: time.Now().UTC(), Valid: true},
+		UserPvtID: existing.UserPvtID,
 	})
+	if err != nil {
+		slog.Error("could not revoke refresh token chain", "error", err)
+		render.RespondFailure(w, http.StatusInternalServerError, internalServerErrorMssg)
+		return
+	}
+	render.RespondSuccess(w, http.StatusOK, map[string]string{"status": "logged out"})
 }
 
+// authIPLimiter throttles every /auth/* request to 30 per minute per
+// client IP. It is process-local and in-memory: at this volume a reset on
+// deploy is an acceptable trade-off for never costing a database round
+// trip on the hot path.
+var authIPLimiter = ratelimit.NewIPLimiter(30, time.Minute)
+
 func AuthRouter() *chi.Mux {
 	authRouter := chi.NewRouter()
+	authRouter.Use(ratelimit.PerIP(authIPLimiter, "auth"))
 
 	authRouter.Post("/login", handleLogin)
-	authRouter.Post("/register", handleRegister)
+	authRouter.Post("/login/mfa", handleLoginMFA)
+	authRouter.With(ratelimit.PerIPPersistent("register", 3, time.Hour)).Post("/register", handleRegister)
+	authRouter.Post("/refresh", handleRefresh)
+	authRouter.Post("/logout", handleLogout)
+	authRouter.Post("/email/verify", handleVerifyEmail)
+	authRouter.With(ratelimit.PerIPPersistent("password-forgot", 3, time.Hour)).Post("/password/forgot", handlePasswordForgot)
+	authRouter.Post("/password/reset", handlePasswordReset)
+	authRouter.Get("/oauth/{provider}/login", handleOAuthLogin)
+	authRouter.Get("/oauth/{provider}/callback", handleOAuthCallback)
 
 	return authRouter
-}
\ No newline at end of file
+}
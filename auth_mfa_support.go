@@ -0,0 +1,98 @@
+package main
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/Suryarpan/chat-api/internal/auth"
+	"github.com/Suryarpan/chat-api/internal/database"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const (
+	// MFAMaxFailedAttempts is how many consecutive bad TOTP/recovery
+	// codes are tolerated before the account is locked out.
+	MFAMaxFailedAttempts = 5
+	// MFALockoutWindow is how long an account stays locked after hitting
+	// MFAMaxFailedAttempts.
+	MFALockoutWindow = 15 * time.Minute
+)
+
+// mfaLockedOut reports whether userPvtID is currently locked out of MFA
+// verification, and if so, how many seconds remain.
+func mfaLockedOut(r *http.Request, queries *database.Queries, userPvtID int32) (locked bool, retryAfterSeconds int64) {
+	lockout, err := queries.GetMFALockout(r.Context(), userPvtID)
+	if err != nil || !lockout.LockedUntil.Valid {
+		return false, 0
+	}
+	remaining := time.Until(lockout.LockedUntil.Time)
+	if remaining <= 0 {
+		return false, 0
+	}
+	return true, int64(remaining.Seconds())
+}
+
+// recordMFAFailure increments the failed-attempt counter for userPvtID,
+// locking it out once MFAMaxFailedAttempts is reached. A streak is only
+// "consecutive" within MFALockoutWindow: if the previous failure is
+// older than that, the counter resets to 1 instead of accumulating
+// forever, so a single mistyped code years apart from the last one
+// doesn't immediately re-lock an account whose prior lockout expired.
+func recordMFAFailure(r *http.Request, queries *database.Queries, userPvtID int32) {
+	now := time.Now().UTC()
+	lockout, err := queries.GetMFALockout(r.Context(), userPvtID)
+	failedAttempts := int32(0)
+	if err == nil && now.Sub(lockout.LastFailureAt) < MFALockoutWindow {
+		failedAttempts = lockout.FailedAttempts
+	}
+	failedAttempts++
+
+	var lockedUntil pgtype.Timestamp
+	if failedAttempts >= MFAMaxFailedAttempts {
+		lockedUntil = pgtype.Timestamp{Time: now.Add(MFALockoutWindow), Valid: true}
+	}
+	_, err = queries.RecordMFAFailure(r.Context(), database.RecordMFAFailureParams{
+		UserPvtID:      userPvtID,
+		FailedAttempts: failedAttempts,
+		LastFailureAt:  now,
+		LockedUntil:    lockedUntil,
+	})
+	if err != nil {
+		slog.Error("could not record mfa failure", "error", err)
+	}
+}
+
+// verifyTOTPOrRecoveryCode checks code against the user's live TOTP
+// secret first, then falls back to any unused recovery code.
+func verifyTOTPOrRecoveryCode(r *http.Request, queries *database.Queries, user database.User, code string) (bool, error) {
+	if secret, err := auth.DecryptTOTPSecret(user.TotpSecret); err == nil {
+		ok, err := auth.ValidateTOTPCode(secret, code)
+		if err != nil {
+			return false, err
+		}
+		if ok {
+			return true, nil
+		}
+	}
+
+	unused, err := queries.ListUnusedRecoveryCodes(r.Context(), user.PvtID)
+	if err != nil {
+		return false, err
+	}
+	hasher := auth.DefaultHasher()
+	for _, recoveryCode := range unused {
+		ok, err := hasher.Verify(string(recoveryCode.CodeHash), []byte(code))
+		if err != nil || !ok {
+			continue
+		}
+		if err := queries.ConsumeRecoveryCode(r.Context(), database.ConsumeRecoveryCodeParams{
+			UsedAt: pgtype.Timestamp{Time: time.Now().UTC(), Valid: true},
+			ID:     recoveryCode.ID,
+		}); err != nil {
+			return false, err
+		}
+		return true, nil
+	}
+	return false, nil
+}
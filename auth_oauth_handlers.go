@@ -0,0 +1,228 @@
+package main
+
+import (
+	"crypto/subtle"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Suryarpan/chat-api/internal/apiconf"
+	"github.com/Suryarpan/chat-api/internal/auth"
+	"github.com/Suryarpan/chat-api/internal/auth/oidc"
+	"github.com/Suryarpan/chat-api/internal/database"
+	"github.com/Suryarpan/chat-api/render"
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"golang.org/x/oauth2"
+)
+
+var (
+	oauthManagerOnce sync.Once
+	oauthManager     *oidc.Manager
+)
+
+// oauthNonceCookie returns the name of the session cookie binding an
+// in-flight OAuth login for provider to the browser that started it, so
+// a forged `state` value (which is otherwise just a redirect-URL
+// parameter visible to anyone) can't complete a login on its own. It is
+// scoped per provider so starting a second provider's login in another
+// tab can't clobber an already-pending one. See
+// internal/auth/oidc.FlowStore for the matching server-side PKCE
+// verifier storage.
+func oauthNonceCookie(provider string) string {
+	return "oauth_nonce_" + provider
+}
+
+// requestIsSecure reports whether r arrived over TLS, either directly or
+// as reported by a trusted reverse proxy terminating TLS in front of us.
+func requestIsSecure(r *http.Request) bool {
+	if r.TLS != nil {
+		return true
+	}
+	return strings.EqualFold(r.Header.Get("X-Forwarded-Proto"), "https")
+}
+
+// oauthManagerFor lazily discovers every OIDC provider configured for this
+// deployment. Discovery needs a live call to each issuer, so it happens
+// once per process rather than on every request.
+func oauthManagerFor(r *http.Request) *oidc.Manager {
+	oauthManagerOnce.Do(func() {
+		configs := apiconf.LoadOIDCProviders()
+		manager, errs := oidc.NewManager(r.Context(), configs)
+		for _, err := range errs {
+			slog.Error("could not initialize oidc provider", "error", err)
+		}
+		oauthManager = manager
+	})
+	return oauthManager
+}
+
+func handleOAuthLogin(w http.ResponseWriter, r *http.Request) {
+	providerName := chi.URLParam(r, "provider")
+	manager := oauthManagerFor(r)
+	provider, ok := manager.Provider(providerName)
+	if !ok {
+		render.RespondFailure(w, http.StatusNotFound, "unknown oauth provider")
+		return
+	}
+
+	verifier := oauth2.GenerateVerifier()
+	nonce, err := oidc.SharedFlowStore().Start(providerName, verifier)
+	if err != nil {
+		slog.Error("could not start oauth flow", "error", err)
+		render.RespondFailure(w, http.StatusInternalServerError, internalServerErrorMssg)
+		return
+	}
+	state := oidc.SignState(providerName, nonce)
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     oauthNonceCookie(providerName),
+		Value:    nonce,
+		Path:     "/auth/oauth",
+		MaxAge:   int(oidc.FlowTTL.Seconds()),
+		HttpOnly: true,
+		Secure:   requestIsSecure(r),
+		SameSite: http.SameSiteLaxMode,
+	})
+	authURL := provider.OAuth2.AuthCodeURL(state, oauth2.S256ChallengeOption(verifier))
+
+	http.Redirect(w, r, authURL, http.StatusFound)
+}
+
+// oauthUsernameFromEmail derives a username candidate from a verified
+// email address for newly provisioned accounts.
+func oauthUsernameFromEmail(email string) string {
+	for i, c := range email {
+		if c == '@' {
+			return email[:i]
+		}
+	}
+	return email
+}
+
+func handleOAuthCallback(w http.ResponseWriter, r *http.Request) {
+	providerName := chi.URLParam(r, "provider")
+	manager := oauthManagerFor(r)
+	provider, ok := manager.Provider(providerName)
+	if !ok {
+		render.RespondFailure(w, http.StatusNotFound, "unknown oauth provider")
+		return
+	}
+
+	state := r.URL.Query().Get("state")
+	code := r.URL.Query().Get("code")
+	nonce, ok := oidc.VerifyState(providerName, state)
+	if !ok {
+		render.RespondFailure(w, http.StatusBadRequest, "invalid oauth state")
+		return
+	}
+
+	cookie, err := r.Cookie(oauthNonceCookie(providerName))
+	http.SetCookie(w, &http.Cookie{
+		Name:     oauthNonceCookie(providerName),
+		Value:    "",
+		Path:     "/auth/oauth",
+		MaxAge:   -1,
+		HttpOnly: true,
+		Secure:   requestIsSecure(r),
+		SameSite: http.SameSiteLaxMode,
+	})
+	if err != nil || subtle.ConstantTimeCompare([]byte(cookie.Value), []byte(nonce)) != 1 {
+		render.RespondFailure(w, http.StatusBadRequest, "invalid oauth state")
+		return
+	}
+
+	verifier, ok := oidc.SharedFlowStore().Claim(providerName, nonce)
+	if !ok {
+		render.RespondFailure(w, http.StatusBadRequest, "invalid or expired oauth state")
+		return
+	}
+
+	userInfo, err := provider.Exchange(r.Context(), code, verifier)
+	if err != nil {
+		slog.Error("oauth exchange failed", "provider", providerName, "error", err)
+		render.RespondFailure(w, http.StatusBadGateway, "could not complete oauth login")
+		return
+	}
+	if !userInfo.EmailVerified || userInfo.Email == "" {
+		render.RespondFailure(w, http.StatusForbidden, "provider did not return a verified email")
+		return
+	}
+	if !provider.EmailAllowed(userInfo.Email) {
+		render.RespondFailure(w, http.StatusForbidden, "email domain is not allowed for this provider")
+		return
+	}
+
+	apiCfg := apiconf.GetConfig(r)
+	queries := database.New(apiCfg.ConnPool)
+	authType := "oidc:" + providerName
+
+	// Already linked: this identity has logged in before.
+	identity, err := queries.GetIdentity(r.Context(), database.GetIdentityParams{
+		Provider: providerName,
+		Subject:  userInfo.Subject,
+	})
+	var user database.User
+	if err == nil {
+		user, err = queries.GetUserByPvtID(r.Context(), identity.UserPvtID)
+		if err != nil {
+			slog.Error("identity points at missing user", "error", err)
+			render.RespondFailure(w, http.StatusInternalServerError, internalServerErrorMssg)
+			return
+		}
+	} else {
+		// Not linked yet: match by verified email, or provision a new user.
+		user, err = queries.GetUserByEmail(r.Context(), pgtype.Text{String: userInfo.Email, Valid: true})
+		if err != nil {
+			now := time.Now().UTC()
+			user, err = queries.CreateOIDCUser(r.Context(), database.CreateOIDCUserParams{
+				Username:        oauthUsernameFromEmail(userInfo.Email),
+				DisplayName:     userInfo.Name,
+				AuthType:        authType,
+				Email:           pgtype.Text{String: userInfo.Email, Valid: true},
+				EmailVerifiedAt: pgtype.Timestamp{Time: now, Valid: true},
+				CreatedAt:       now,
+				UpdatedAt:       now,
+			})
+			if err != nil {
+				slog.Error("could not provision oidc user", "error", err)
+				render.RespondFailure(w, http.StatusInsufficientStorage, insufficientStorageErrorMssg)
+				return
+			}
+		}
+		if _, err := queries.CreateIdentity(r.Context(), database.CreateIdentityParams{
+			Provider:  providerName,
+			Subject:   userInfo.Subject,
+			UserPvtID: user.PvtID,
+			CreatedAt: time.Now().UTC(),
+		}); err != nil {
+			slog.Error("could not link oidc identity", "error", err)
+			render.RespondFailure(w, http.StatusInsufficientStorage, insufficientStorageErrorMssg)
+			return
+		}
+	}
+
+	accessToken, err := auth.UserToToken(user)
+	if err != nil {
+		render.RespondFailure(w, http.StatusInternalServerError, tokenGenerationErrorMssg)
+		return
+	}
+	refreshToken, err := issueSession(r, queries, user, pgtype.UUID{})
+	if err != nil {
+		slog.Error("could not issue refresh token", "error", err)
+		render.RespondFailure(w, http.StatusInternalServerError, tokenGenerationErrorMssg)
+		return
+	}
+	render.RespondSuccess(w, http.StatusOK, loginResponse{
+		AccessToken:      accessToken,
+		TokenType:        auth.TokenPrefix,
+		RefreshToken:     refreshToken,
+		ExpiresIn:        int64(auth.AccessTokenTTL.Seconds()),
+		RefreshExpiresIn: int64(auth.RefreshTokenTTL.Seconds()),
+		Username:         user.Username,
+		DisplayName:      user.DisplayName,
+		LastLoggedIn:     user.LastLoggedIn,
+	})
+}
@@ -0,0 +1,56 @@
+package apiconf
+
+import (
+	"os"
+	"strings"
+)
+
+// OIDCProviderConfig describes a single configured OAuth2/OIDC identity
+// provider, as surfaced under internal/auth/oidc.
+type OIDCProviderConfig struct {
+	Name                string
+	IssuerURL           string
+	ClientID            string
+	ClientSecret        string
+	RedirectURL         string
+	Scopes              []string
+	AllowedEmailDomains []string
+}
+
+// LoadOIDCProviders reads the set of configured providers from the
+// environment. OIDC_PROVIDERS is a comma-separated list of provider names;
+// each name's settings are read from OIDC_<NAME>_ISSUER_URL,
+// OIDC_<NAME>_CLIENT_ID, OIDC_<NAME>_CLIENT_SECRET, OIDC_<NAME>_REDIRECT_URL,
+// OIDC_<NAME>_SCOPES (comma-separated) and OIDC_<NAME>_ALLOWED_EMAIL_DOMAINS
+// (comma-separated, optional).
+func LoadOIDCProviders() map[string]OIDCProviderConfig {
+	providers := map[string]OIDCProviderConfig{}
+	names := splitAndTrim(os.Getenv("OIDC_PROVIDERS"))
+	for _, name := range names {
+		key := strings.ToUpper(name)
+		providers[name] = OIDCProviderConfig{
+			Name:                name,
+			IssuerURL:           os.Getenv("OIDC_" + key + "_ISSUER_URL"),
+			ClientID:            os.Getenv("OIDC_" + key + "_CLIENT_ID"),
+			ClientSecret:        os.Getenv("OIDC_" + key + "_CLIENT_SECRET"),
+			RedirectURL:         os.Getenv("OIDC_" + key + "_REDIRECT_URL"),
+			Scopes:              splitAndTrim(os.Getenv("OIDC_" + key + "_SCOPES")),
+			AllowedEmailDomains: splitAndTrim(os.Getenv("OIDC_" + key + "_ALLOWED_EMAIL_DOMAINS")),
+		}
+	}
+	return providers
+}
+
+func splitAndTrim(raw string) []string {
+	if raw == "" {
+		return nil
+	}
+	parts := strings.Split(raw, ",")
+	out := make([]string, 0, len(parts))
+	for _, p := range parts {
+		if trimmed := strings.TrimSpace(p); trimmed != "" {
+			out = append(out, trimmed)
+		}
+	}
+	return out
+}
@@ -0,0 +1,88 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MFATokenTTL is how long a mfa_token issued by handleLogin stays valid
+// for the follow-up POST /auth/login/mfa call.
+const MFATokenTTL = 5 * time.Minute
+
+var (
+	mfaSecretOnce sync.Once
+	mfaSecret     []byte
+)
+
+func mfaTokenSecret() []byte {
+	mfaSecretOnce.Do(func() {
+		mfaSecret = []byte(os.Getenv("MFA_TOKEN_SECRET"))
+	})
+	return mfaSecret
+}
+
+// NewMFAToken mints a short-lived, HMAC-signed token scoped to the
+// mfa-verify step for the given user, so the client can complete login
+// without re-sending the password.
+func NewMFAToken(userPvtID int32) string {
+	payload := fmt.Sprintf("mfa-verify|%d|%d", userPvtID, time.Now().Add(MFATokenTTL).Unix())
+	return signPayload(payload)
+}
+
+// ParseMFAToken validates a mfa_token and returns the user it was issued
+// for. It fails closed on any tampering, wrong scope, or expiry.
+func ParseMFAToken(token string) (userPvtID int32, err error) {
+	payload, ok := verifyPayload(token)
+	if !ok {
+		return 0, fmt.Errorf("invalid mfa token")
+	}
+	parts := strings.Split(payload, "|")
+	if len(parts) != 3 || parts[0] != "mfa-verify" {
+		return 0, fmt.Errorf("invalid mfa token scope")
+	}
+	id, err := strconv.ParseInt(parts[1], 10, 32)
+	if err != nil {
+		return 0, fmt.Errorf("invalid mfa token subject: %w", err)
+	}
+	expiry, err := strconv.ParseInt(parts[2], 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid mfa token expiry: %w", err)
+	}
+	if time.Now().Unix() > expiry {
+		return 0, fmt.Errorf("mfa token has expired")
+	}
+	return int32(id), nil
+}
+
+func signPayload(payload string) string {
+	mac := hmac.New(sha256.New, mfaTokenSecret())
+	mac.Write([]byte(payload))
+	sig := base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+	return base64.RawURLEncoding.EncodeToString([]byte(payload)) + "." + sig
+}
+
+func verifyPayload(token string) (payload string, ok bool) {
+	parts := strings.SplitN(token, ".", 2)
+	if len(parts) != 2 {
+		return "", false
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return "", false
+	}
+	mac := hmac.New(sha256.New, mfaTokenSecret())
+	mac.Write(raw)
+	expected := base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+	if subtle.ConstantTimeCompare([]byte(expected), []byte(parts[1])) != 1 {
+		return "", false
+	}
+	return string(raw), true
+}
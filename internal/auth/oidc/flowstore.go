@@ -0,0 +1,100 @@
+package oidc
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"sync"
+	"time"
+)
+
+// FlowTTL bounds how long a pending login is allowed to sit between the
+// redirect to the provider and the user completing it there.
+const FlowTTL = 10 * time.Minute
+
+type pendingFlow struct {
+	provider  string
+	verifier  string
+	expiresAt time.Time
+}
+
+// FlowStore holds the PKCE verifier for each in-flight login, keyed by a
+// one-time nonce. Keeping the verifier here instead of round-tripping it
+// through the `state` parameter means it never appears in a redirect URL,
+// browser history, or access/referrer logs.
+type FlowStore struct {
+	mu    sync.Mutex
+	flows map[string]pendingFlow
+	swept time.Time
+}
+
+// NewFlowStore builds an empty, process-local FlowStore.
+func NewFlowStore() *FlowStore {
+	return &FlowStore{flows: make(map[string]pendingFlow)}
+}
+
+var (
+	sharedFlowStoreOnce sync.Once
+	sharedFlowStore     *FlowStore
+)
+
+// SharedFlowStore returns the process-wide FlowStore used by the OAuth
+// handlers.
+func SharedFlowStore() *FlowStore {
+	sharedFlowStoreOnce.Do(func() {
+		sharedFlowStore = NewFlowStore()
+	})
+	return sharedFlowStore
+}
+
+// generateNonce returns a URL-safe random token suitable for both the
+// FlowStore key and the session-binding cookie value.
+func generateNonce() (string, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(raw), nil
+}
+
+// Start records a new pending login for provider and returns the nonce
+// the caller must bind to the user's browser (e.g. via a cookie) and echo
+// back through `state`.
+func (s *FlowStore) Start(provider, verifier string) (nonce string, err error) {
+	nonce, err = generateNonce()
+	if err != nil {
+		return "", err
+	}
+	now := time.Now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if now.Sub(s.swept) > FlowTTL {
+		s.sweep(now)
+	}
+	s.flows[nonce] = pendingFlow{provider: provider, verifier: verifier, expiresAt: now.Add(FlowTTL)}
+	return nonce, nil
+}
+
+// Claim consumes the pending login for nonce, returning its PKCE verifier.
+// It fails if the nonce is unknown, expired, or was issued for a
+// different provider than the callback is completing.
+func (s *FlowStore) Claim(provider, nonce string) (verifier string, ok bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	flow, found := s.flows[nonce]
+	delete(s.flows, nonce)
+	if !found || flow.provider != provider || time.Now().After(flow.expiresAt) {
+		return "", false
+	}
+	return flow.verifier, true
+}
+
+// sweep drops expired flows; callers hold s.mu.
+func (s *FlowStore) sweep(now time.Time) {
+	s.swept = now
+	for nonce, flow := range s.flows {
+		if now.After(flow.expiresAt) {
+			delete(s.flows, nonce)
+		}
+	}
+}
@@ -0,0 +1,191 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/pbkdf2"
+)
+
+// PasswordHasher hashes and verifies passwords behind a pluggable algorithm
+// so the cost parameters (or the algorithm itself) can be rotated without
+// invalidating passwords that were hashed under older settings.
+type PasswordHasher interface {
+	// Hash returns a self-describing, PHC-style encoded string.
+	Hash(password []byte) (string, error)
+	// Verify reports whether password matches the given encoded hash.
+	Verify(encoded string, password []byte) (bool, error)
+	// NeedsRehash reports whether encoded was produced with parameters
+	// older than the hasher's current configuration.
+	NeedsRehash(encoded string) bool
+}
+
+// Argon2idParams holds the cost parameters baked into every hash produced
+// by Argon2idHasher.
+type Argon2idParams struct {
+	Memory      uint32
+	Time        uint32
+	Parallelism uint8
+	SaltLen     uint32
+	KeyLen      uint32
+}
+
+// DefaultArgon2idParams are the parameters new hashes are produced with.
+var DefaultArgon2idParams = Argon2idParams{
+	Memory:      64 * 1024, // 64MiB
+	Time:        3,
+	Parallelism: 2,
+	SaltLen:     16,
+	KeyLen:      32,
+}
+
+const argon2idPrefix = "$argon2id$"
+
+// Argon2idHasher is the default PasswordHasher. Passwords are first run
+// through an application-wide HMAC-SHA256 pepper so that a database-only
+// leak is not sufficient to brute-force the hashes offline.
+type Argon2idHasher struct {
+	params Argon2idParams
+	pepper []byte
+}
+
+// NewArgon2idHasher builds an Argon2idHasher using DefaultArgon2idParams
+// and the given pepper key.
+func NewArgon2idHasher(pepper []byte) *Argon2idHasher {
+	return &Argon2idHasher{params: DefaultArgon2idParams, pepper: pepper}
+}
+
+func (h *Argon2idHasher) pepperedPassword(password []byte) []byte {
+	mac := hmac.New(sha256.New, h.pepper)
+	mac.Write(password)
+	return mac.Sum(nil)
+}
+
+func (h *Argon2idHasher) Hash(password []byte) (string, error) {
+	salt := make([]byte, h.params.SaltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return "", fmt.Errorf("generate salt: %w", err)
+	}
+	hash := argon2.IDKey(h.pepperedPassword(password), salt, h.params.Time, h.params.Memory, h.params.Parallelism, h.params.KeyLen)
+	return encodeArgon2id(h.params, salt, hash), nil
+}
+
+func (h *Argon2idHasher) Verify(encoded string, password []byte) (bool, error) {
+	params, salt, hash, err := decodeArgon2id(encoded)
+	if err != nil {
+		return false, err
+	}
+	computed := argon2.IDKey(h.pepperedPassword(password), salt, params.Time, params.Memory, params.Parallelism, uint32(len(hash)))
+	return subtle.ConstantTimeCompare(computed, hash) == 1, nil
+}
+
+func (h *Argon2idHasher) NeedsRehash(encoded string) bool {
+	params, _, _, err := decodeArgon2id(encoded)
+	if err != nil {
+		return true
+	}
+	return params != h.params
+}
+
+func encodeArgon2id(p Argon2idParams, salt, hash []byte) string {
+	return fmt.Sprintf(
+		"$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version, p.Memory, p.Time, p.Parallelism,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(hash),
+	)
+}
+
+func decodeArgon2id(encoded string) (Argon2idParams, []byte, []byte, error) {
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 6 || parts[1] != "argon2id" {
+		return Argon2idParams{}, nil, nil, fmt.Errorf("malformed argon2id hash")
+	}
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil {
+		return Argon2idParams{}, nil, nil, fmt.Errorf("malformed argon2id version: %w", err)
+	}
+	if version != argon2.Version {
+		return Argon2idParams{}, nil, nil, fmt.Errorf("unsupported argon2id version %d", version)
+	}
+	var p Argon2idParams
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &p.Memory, &p.Time, &p.Parallelism); err != nil {
+		return Argon2idParams{}, nil, nil, fmt.Errorf("malformed argon2id params: %w", err)
+	}
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return Argon2idParams{}, nil, nil, fmt.Errorf("malformed argon2id salt: %w", err)
+	}
+	hash, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil {
+		return Argon2idParams{}, nil, nil, fmt.Errorf("malformed argon2id hash: %w", err)
+	}
+	p.SaltLen = uint32(len(salt))
+	p.KeyLen = uint32(len(hash))
+	return p, salt, hash, nil
+}
+
+// legacyPBKDF2Iterations matches the fixed cost the original saltyPassword
+// helper used before hashes were migrated to Argon2id.
+const legacyPBKDF2Iterations = 10_000
+
+// verifyLegacyPBKDF2 checks a password against the pre-Argon2id encoding,
+// where the hash and salt were stored as raw bytes in separate columns.
+func verifyLegacyPBKDF2(stored, salt, password []byte) bool {
+	if len(stored) == 0 {
+		return false
+	}
+	computed := pbkdf2.Key(password, salt, legacyPBKDF2Iterations, len(stored), sha256.New)
+	return len(computed) > 0 && subtle.ConstantTimeCompare(computed, stored) == 1
+}
+
+// IsLegacyEncoding reports whether stored holds a pre-Argon2id PBKDF2
+// hash rather than a PHC-style encoded string.
+func IsLegacyEncoding(stored []byte) bool {
+	return len(stored) == 0 || !strings.HasPrefix(string(stored), argon2idPrefix)
+}
+
+// VerifyPassword checks password against stored, transparently handling
+// both the current Argon2id encoding and the legacy PBKDF2+salt encoding.
+// rehash reports whether the caller should re-persist the password under
+// the hasher's current parameters: always true on a successful legacy
+// verification, or true when the stored Argon2id hash used older params.
+func VerifyPassword(hasher PasswordHasher, stored, salt, password []byte) (ok bool, rehash bool, err error) {
+	if IsLegacyEncoding(stored) {
+		ok = verifyLegacyPBKDF2(stored, salt, password)
+		return ok, ok, nil
+	}
+	ok, err = hasher.Verify(string(stored), password)
+	if err != nil {
+		return false, false, err
+	}
+	return ok, ok && hasher.NeedsRehash(string(stored)), nil
+}
+
+var (
+	defaultHasherOnce sync.Once
+	defaultHasher     *Argon2idHasher
+)
+
+// DefaultHasher returns the process-wide Argon2idHasher, peppered with the
+// key configured via the PASSWORD_PEPPER environment variable.
+func DefaultHasher() *Argon2idHasher {
+	defaultHasherOnce.Do(func() {
+		defaultHasher = NewArgon2idHasher([]byte(os.Getenv("PASSWORD_PEPPER")))
+	})
+	return defaultHasher
+}
+
+// HashPassword hashes password with the default hasher, returning the
+// PHC-style encoded string to store in the `password` column.
+func HashPassword(password []byte) (string, error) {
+	return DefaultHasher().Hash(password)
+}
@@ -0,0 +1,191 @@
+package auth
+
+import (
+	"crypto/sha256"
+	"testing"
+
+	"golang.org/x/crypto/pbkdf2"
+)
+
+func TestEncodeDecodeArgon2idRoundTrip(t *testing.T) {
+	params := Argon2idParams{Memory: 64 * 1024, Time: 3, Parallelism: 2, SaltLen: 16, KeyLen: 32}
+	salt := make([]byte, params.SaltLen)
+	hash := make([]byte, params.KeyLen)
+	for i := range salt {
+		salt[i] = byte(i)
+	}
+	for i := range hash {
+		hash[i] = byte(255 - i)
+	}
+
+	encoded := encodeArgon2id(params, salt, hash)
+	gotParams, gotSalt, gotHash, err := decodeArgon2id(encoded)
+	if err != nil {
+		t.Fatalf("decodeArgon2id(%q): %v", encoded, err)
+	}
+	if gotParams != params {
+		t.Fatalf("params = %+v, want %+v", gotParams, params)
+	}
+	if string(gotSalt) != string(salt) {
+		t.Fatalf("salt = %x, want %x", gotSalt, salt)
+	}
+	if string(gotHash) != string(hash) {
+		t.Fatalf("hash = %x, want %x", gotHash, hash)
+	}
+}
+
+func TestDecodeArgon2idMalformed(t *testing.T) {
+	cases := map[string]string{
+		"wrong segment count": "$argon2id$v=19$m=1,t=1,p=1$salt$hash$extra",
+		"wrong algorithm":     "$bcrypt$v=19$m=1,t=1,p=1$c2FsdA$aGFzaA",
+		"bad version":         "$argon2id$v=nope$m=1,t=1,p=1$c2FsdA$aGFzaA",
+		"unsupported version": "$argon2id$v=1$m=1,t=1,p=1$c2FsdA$aGFzaA",
+		"bad params":          "$argon2id$v=19$nonsense$c2FsdA$aGFzaA",
+		"bad salt":            "$argon2id$v=19$m=1,t=1,p=1$not-base64!$aGFzaA",
+		"bad hash":            "$argon2id$v=19$m=1,t=1,p=1$c2FsdA$not-base64!",
+	}
+	for name, encoded := range cases {
+		t.Run(name, func(t *testing.T) {
+			if _, _, _, err := decodeArgon2id(encoded); err == nil {
+				t.Fatalf("decodeArgon2id(%q): want error, got nil", encoded)
+			}
+		})
+	}
+}
+
+func TestArgon2idHasherVerify(t *testing.T) {
+	hasher := NewArgon2idHasher([]byte("pepper"))
+	password := []byte("correct horse battery staple")
+
+	encoded, err := hasher.Hash(password)
+	if err != nil {
+		t.Fatalf("Hash: %v", err)
+	}
+
+	ok, err := hasher.Verify(encoded, password)
+	if err != nil {
+		t.Fatalf("Verify(correct password): %v", err)
+	}
+	if !ok {
+		t.Fatal("Verify(correct password) = false, want true")
+	}
+
+	ok, err = hasher.Verify(encoded, []byte("wrong password"))
+	if err != nil {
+		t.Fatalf("Verify(wrong password): %v", err)
+	}
+	if ok {
+		t.Fatal("Verify(wrong password) = true, want false")
+	}
+
+	if _, err := hasher.Verify("not an encoded hash", password); err == nil {
+		t.Fatal("Verify(malformed encoding): want error, got nil")
+	}
+}
+
+func TestArgon2idHasherNeedsRehash(t *testing.T) {
+	hasher := NewArgon2idHasher([]byte("pepper"))
+	encoded, err := hasher.Hash([]byte("password"))
+	if err != nil {
+		t.Fatalf("Hash: %v", err)
+	}
+	if hasher.NeedsRehash(encoded) {
+		t.Fatal("NeedsRehash on a hash produced with current params = true, want false")
+	}
+
+	stale := NewArgon2idHasher([]byte("pepper"))
+	stale.params.Time++
+	staleEncoded, err := stale.Hash([]byte("password"))
+	if err != nil {
+		t.Fatalf("Hash: %v", err)
+	}
+	if !hasher.NeedsRehash(staleEncoded) {
+		t.Fatal("NeedsRehash on a hash produced with drifted params = false, want true")
+	}
+
+	if !hasher.NeedsRehash("not an encoded hash") {
+		t.Fatal("NeedsRehash on a malformed encoding = false, want true")
+	}
+}
+
+func TestVerifyPasswordArgon2id(t *testing.T) {
+	hasher := NewArgon2idHasher([]byte("pepper"))
+	password := []byte("correct horse battery staple")
+	encoded, err := hasher.Hash(password)
+	if err != nil {
+		t.Fatalf("Hash: %v", err)
+	}
+
+	ok, rehash, err := VerifyPassword(hasher, []byte(encoded), nil, password)
+	if err != nil {
+		t.Fatalf("VerifyPassword: %v", err)
+	}
+	if !ok {
+		t.Fatal("VerifyPassword(correct) ok = false, want true")
+	}
+	if rehash {
+		t.Fatal("VerifyPassword(correct, current params) rehash = true, want false")
+	}
+
+	ok, _, err = VerifyPassword(hasher, []byte(encoded), nil, []byte("wrong"))
+	if err != nil {
+		t.Fatalf("VerifyPassword: %v", err)
+	}
+	if ok {
+		t.Fatal("VerifyPassword(wrong password) ok = true, want false")
+	}
+
+	stale := NewArgon2idHasher([]byte("pepper"))
+	stale.params.Time++
+	staleEncoded, err := stale.Hash(password)
+	if err != nil {
+		t.Fatalf("Hash: %v", err)
+	}
+	ok, rehash, err = VerifyPassword(hasher, []byte(staleEncoded), nil, password)
+	if err != nil {
+		t.Fatalf("VerifyPassword: %v", err)
+	}
+	if !ok {
+		t.Fatal("VerifyPassword(correct, stale params) ok = false, want true")
+	}
+	if !rehash {
+		t.Fatal("VerifyPassword(correct, stale params) rehash = false, want true")
+	}
+}
+
+func TestVerifyPasswordLegacyPBKDF2(t *testing.T) {
+	hasher := NewArgon2idHasher([]byte("pepper"))
+	salt := []byte("legacy-salt")
+	password := []byte("correct horse battery staple")
+	stored := pbkdf2.Key(password, salt, legacyPBKDF2Iterations, 32, sha256.New)
+
+	ok, rehash, err := VerifyPassword(hasher, stored, salt, password)
+	if err != nil {
+		t.Fatalf("VerifyPassword: %v", err)
+	}
+	if !ok {
+		t.Fatal("VerifyPassword(legacy, correct) ok = false, want true")
+	}
+	if !rehash {
+		t.Fatal("VerifyPassword(legacy, correct) rehash = false, want true")
+	}
+
+	ok, rehash, err = VerifyPassword(hasher, stored, salt, []byte("wrong"))
+	if err != nil {
+		t.Fatalf("VerifyPassword: %v", err)
+	}
+	if ok {
+		t.Fatal("VerifyPassword(legacy, wrong password) ok = true, want false")
+	}
+	if rehash {
+		t.Fatal("VerifyPassword(legacy, wrong password) rehash = true, want false")
+	}
+
+	ok, rehash, err = VerifyPassword(hasher, nil, salt, password)
+	if err != nil {
+		t.Fatalf("VerifyPassword: %v", err)
+	}
+	if ok || rehash {
+		t.Fatalf("VerifyPassword(empty stored) = (%v, %v), want (false, false)", ok, rehash)
+	}
+}
@@ -0,0 +1,23 @@
+package auth
+
+import (
+	"crypto/rand"
+	"fmt"
+)
+
+const recoveryCodeAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+
+// GenerateRecoveryCode returns a random 8-char one-time MFA recovery code.
+// It avoids visually ambiguous characters (0/O, 1/I/L) since these are
+// meant to be typed by hand.
+func GenerateRecoveryCode() (string, error) {
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("generate recovery code: %w", err)
+	}
+	code := make([]byte, 8)
+	for i, b := range buf {
+		code[i] = recoveryCodeAlphabet[int(b)%len(recoveryCodeAlphabet)]
+	}
+	return string(code), nil
+}
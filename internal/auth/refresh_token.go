@@ -0,0 +1,38 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"time"
+)
+
+const (
+	// AccessTokenTTL is how long an access token issued at login remains
+	// valid before the client must refresh.
+	AccessTokenTTL = 15 * time.Minute
+	// RefreshTokenTTL is how long a refresh token can be redeemed for a
+	// fresh access token before the session must be re-authenticated.
+	RefreshTokenTTL = 30 * 24 * time.Hour
+
+	refreshTokenBytes = 32
+)
+
+// NewRefreshToken returns a random opaque refresh token along with the
+// SHA-256 hash that should be persisted in place of the raw value.
+func NewRefreshToken() (plain string, hash []byte, err error) {
+	raw := make([]byte, refreshTokenBytes)
+	if _, err := rand.Read(raw); err != nil {
+		return "", nil, fmt.Errorf("generate refresh token: %w", err)
+	}
+	plain = base64.RawURLEncoding.EncodeToString(raw)
+	return plain, HashRefreshToken(plain), nil
+}
+
+// HashRefreshToken returns the SHA-256 digest of a refresh token as
+// presented by the client, for lookup/comparison against stored hashes.
+func HashRefreshToken(plain string) []byte {
+	sum := sha256.Sum256([]byte(plain))
+	return sum[:]
+}
@@ -0,0 +1,39 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"time"
+)
+
+const (
+	// EmailVerificationTTL is how long a registration's verification link
+	// remains valid before the user must request a new one.
+	EmailVerificationTTL = 24 * time.Hour
+	// PasswordResetTTL is how long a "forgot password" link remains valid.
+	PasswordResetTTL = 15 * time.Minute
+
+	verificationTokenBytes = 32
+)
+
+// NewVerificationToken returns a random opaque token along with the
+// SHA-256 hash that should be persisted in place of the raw value. It is
+// used for both email-verification and password-reset links, which share
+// the same "mail out a one-time opaque link" shape as refresh tokens.
+func NewVerificationToken() (plain string, hash []byte, err error) {
+	raw := make([]byte, verificationTokenBytes)
+	if _, err := rand.Read(raw); err != nil {
+		return "", nil, fmt.Errorf("generate verification token: %w", err)
+	}
+	plain = base64.RawURLEncoding.EncodeToString(raw)
+	return plain, HashVerificationToken(plain), nil
+}
+
+// HashVerificationToken returns the SHA-256 digest of a verification token
+// as presented by the client, for lookup/comparison against stored hashes.
+func HashVerificationToken(plain string) []byte {
+	sum := sha256.Sum256([]byte(plain))
+	return sum[:]
+}
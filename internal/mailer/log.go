@@ -0,0 +1,20 @@
+package mailer
+
+import (
+	"context"
+	"log/slog"
+)
+
+// LogMailer logs emails instead of sending them, for local development and
+// tests where no real SMTP backend is configured.
+type LogMailer struct{}
+
+// NewLogMailer builds a no-op Mailer.
+func NewLogMailer() *LogMailer {
+	return &LogMailer{}
+}
+
+func (m *LogMailer) Send(_ context.Context, to, subject, body string) error {
+	slog.Info("mailer: would send email", "to", to, "subject", subject, "body", body)
+	return nil
+}
@@ -0,0 +1,34 @@
+// Package mailer sends transactional email (verification links, password
+// resets) through a pluggable backend selected via environment config.
+package mailer
+
+import (
+	"context"
+	"os"
+	"sync"
+)
+
+// Mailer sends a single plain-text email.
+type Mailer interface {
+	Send(ctx context.Context, to, subject, body string) error
+}
+
+var (
+	defaultOnce sync.Once
+	def         Mailer
+)
+
+// Default returns the process-wide Mailer selected by MAILER_DRIVER
+// ("smtp" or "log"). It defaults to the log driver so the application
+// still runs, loudly, if SMTP hasn't been configured.
+func Default() Mailer {
+	defaultOnce.Do(func() {
+		switch os.Getenv("MAILER_DRIVER") {
+		case "smtp":
+			def = newSMTPMailerFromEnv()
+		default:
+			def = NewLogMailer()
+		}
+	})
+	return def
+}
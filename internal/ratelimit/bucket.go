@@ -0,0 +1,81 @@
+// Package ratelimit provides abuse-protection middleware for the auth
+// endpoints: an in-memory token bucket for high-frequency per-IP limits,
+// and a Postgres-backed sliding window for per-username lockouts that
+// must survive process restarts and be shared across instances.
+package ratelimit
+
+import (
+	"sync"
+	"time"
+)
+
+// IPLimiter is an in-memory token bucket limiter keyed by an arbitrary
+// string (typically "ip|route"). It is process-local by design: the
+// request-rate limits it enforces are generous enough that a brief reset
+// on deploy is not a concern, and checking it never costs a round trip.
+type IPLimiter struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	rate      float64 // tokens replenished per second
+	burst     float64 // bucket capacity
+	lastSwept time.Time
+}
+
+// sweepInterval is how often Allow opportunistically evicts buckets that
+// have been idle long enough to be back at full capacity, so an attacker
+// cycling through source IPs can't grow the map without bound.
+const sweepInterval = 5 * time.Minute
+
+type bucket struct {
+	tokens   float64
+	lastSeen time.Time
+}
+
+// NewIPLimiter builds a limiter that allows up to limit requests per
+// window, per key, bursting up to the full limit immediately.
+func NewIPLimiter(limit int, window time.Duration) *IPLimiter {
+	return &IPLimiter{
+		buckets: make(map[string]*bucket),
+		rate:    float64(limit) / window.Seconds(),
+		burst:   float64(limit),
+	}
+}
+
+// Allow reports whether a request under key is within the limit, and
+// consumes a token from its bucket if so.
+func (l *IPLimiter) Allow(key string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	if now.Sub(l.lastSwept) > sweepInterval {
+		l.sweep(now)
+	}
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: l.burst, lastSeen: now}
+		l.buckets[key] = b
+	}
+	elapsed := now.Sub(b.lastSeen).Seconds()
+	b.lastSeen = now
+	b.tokens += elapsed * l.rate
+	if b.tokens > l.burst {
+		b.tokens = l.burst
+	}
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// sweep drops buckets that have been idle long enough to have refilled to
+// capacity; callers hold l.mu.
+func (l *IPLimiter) sweep(now time.Time) {
+	l.lastSwept = now
+	for key, b := range l.buckets {
+		if now.Sub(b.lastSeen).Seconds()*l.rate >= l.burst {
+			delete(l.buckets, key)
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/Suryarpan/chat-api/internal/database"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Limiter is a sliding-window counter keyed by an arbitrary string,
+// persisted in Postgres so limits survive restarts and are shared across
+// instances. Every RecordAttempt call increments the persisted counter;
+// nothing short-circuits the write, since that counter is what the
+// lockout threshold is measured against.
+type Limiter struct {
+	queries *database.Queries
+}
+
+// New builds a Postgres-backed Limiter.
+func New(pool *pgxpool.Pool) *Limiter {
+	return &Limiter{queries: database.New(pool)}
+}
+
+var (
+	sharedOnce sync.Once
+	shared     *Limiter
+)
+
+// Shared returns a process-wide Limiter backed by pool. Call sites share
+// a single instance so the underlying connection pool is reused instead
+// of opening one per request.
+func Shared(pool *pgxpool.Pool) *Limiter {
+	sharedOnce.Do(func() {
+		shared = New(pool)
+	})
+	return shared
+}
+
+// recordAndCount atomically increments the counter for key within window
+// in a single round trip and returns the resulting count and bucket start,
+// so callers can apply their own over-limit comparison without a separate
+// read that would race against concurrent increments.
+func (l *Limiter) recordAndCount(ctx context.Context, key string, window time.Duration) (count int, bucketStart time.Time, err error) {
+	now := time.Now().UTC()
+	cutoff := now.Add(-window)
+	row, err := l.queries.RecordAuthAttempt(ctx, database.RecordAuthAttemptParams{
+		Key:          key,
+		BucketStart:  now,
+		WindowCutoff: cutoff,
+	})
+	if err != nil {
+		return 0, time.Time{}, err
+	}
+	return int(row.Count), row.BucketStart, nil
+}
+
+// RecordAttempt increments the counter for key within window and reports
+// whether the caller is now over limit, along with how long until the
+// window resets.
+func (l *Limiter) RecordAttempt(ctx context.Context, key string, limit int, window time.Duration) (overLimit bool, retryAfter time.Duration, err error) {
+	count, bucketStart, err := l.recordAndCount(ctx, key, window)
+	if err != nil {
+		return false, 0, err
+	}
+	if count >= limit {
+		retryAfter = window - time.Now().UTC().Sub(bucketStart)
+		if retryAfter < 0 {
+			retryAfter = 0
+		}
+		return true, retryAfter, nil
+	}
+	return false, 0, nil
+}
+
+// Allow atomically records an attempt for key and reports whether it falls
+// within the first limit attempts in window, in a single round trip. Unlike
+// the IsOverLimit-then-RecordAttempt pair the login flow uses (which must
+// check before an attempt that's expensive to retry is even made), Allow is
+// for gating cheap requests where the attempt itself is the thing being
+// counted, so exactly limit requests succeed and the (limit+1)th is
+// rejected without a separate, race-prone pre-check read.
+func (l *Limiter) Allow(ctx context.Context, key string, limit int, window time.Duration) (allowed bool, retryAfter time.Duration, err error) {
+	count, bucketStart, err := l.recordAndCount(ctx, key, window)
+	if err != nil {
+		return false, 0, err
+	}
+	if count > limit {
+		retryAfter = window - time.Now().UTC().Sub(bucketStart)
+		if retryAfter < 0 {
+			retryAfter = 0
+		}
+		return false, retryAfter, nil
+	}
+	return true, 0, nil
+}
+
+// IsOverLimit reports the current state of key without incrementing it,
+// for checking before attempting an action that is expensive to retry.
+func (l *Limiter) IsOverLimit(ctx context.Context, key string, limit int, window time.Duration) (overLimit bool, retryAfter time.Duration, err error) {
+	row, err := l.queries.GetAuthAttempt(ctx, key)
+	if err != nil {
+		return false, 0, nil
+	}
+	if int(row.Count) < limit {
+		return false, 0, nil
+	}
+	retryAfter = window - time.Since(row.BucketStart)
+	if retryAfter < 0 {
+		return false, 0, nil
+	}
+	return true, retryAfter, nil
+}
+
+// Reset clears key's counter, e.g. after a successful login.
+func (l *Limiter) Reset(ctx context.Context, key string) error {
+	return l.queries.ResetAuthAttempt(ctx, key)
+}
@@ -1,7 +1,6 @@
 package main
 
 import (
-	"crypto/rand"
 	"encoding/json"
 	"errors"
 	"log/slog"
@@ -11,6 +10,7 @@ import (
 	"github.com/Suryarpan/chat-api/internal/apiconf"
 	"github.com/Suryarpan/chat-api/internal/auth"
 	"github.com/Suryarpan/chat-api/internal/database"
+	"github.com/Suryarpan/chat-api/internal/ratelimit"
 	"github.com/Suryarpan/chat-api/render"
 	"github.com/go-chi/chi/v5"
 	"github.com/go-playground/validator/v10"
@@ -52,9 +52,15 @@ func handleGetUserDetail(w http.ResponseWriter, r *http.Request) {
 type createUserData struct {
 	Username    string `json:"username" validate:"required,min=5,max=50"`
 	DisplayName string `json:"display_name" validate:"required,min=5,max=150"`
+	Email       string `json:"email" validate:"required,email"`
 	Password    string `json:"password" validate:"required,printascii,min=8"`
 }
 
+// handleCreateUser is an admin/service variant of handleRegister and
+// shares its anti-enumeration shape: a well-formed request always gets
+// the same 202 regardless of whether the username or email was already
+// taken, and the password is hashed before either lookup so a taken
+// account doesn't resolve any faster than a free one.
 func handleCreateUser(w http.ResponseWriter, r *http.Request) {
 	cu := createUserData{}
 	reader := json.NewDecoder(r.Body)
@@ -73,29 +79,29 @@ func handleCreateUser(w http.ResponseWriter, r *http.Request) {
 		}
 		return
 	}
-	// check user name with DB
-	queries := database.New(apiCfg.ConnPool)
-	_, err = queries.GetUserByName(r.Context(), cu.Username)
-	if err == nil {
-		render.RespondFailure(w, http.StatusNotAcceptable, map[string]string{"username": "already exists"})
-		return
-	}
-	// generate the password hash
-	passwordSalt := make([]byte, 128)
-	_, err = rand.Read(passwordSalt)
+
+	password, err := auth.HashPassword([]byte(cu.Password))
 	if err != nil {
+		slog.Error("error hashing password", "error", err)
 		render.RespondFailure(w, http.StatusInsufficientStorage, insufficientStorageErrorMssg)
 		return
 	}
-	password := auth.SaltyPassword([]byte(cu.Password), passwordSalt)
-	// store in DB
+
+	queries := database.New(apiCfg.ConnPool)
+	_, nameErr := queries.GetUserByName(r.Context(), cu.Username)
+	_, emailErr := queries.GetUserByEmail(r.Context(), pgtype.Text{String: cu.Email, Valid: true})
+	if nameErr == nil || emailErr == nil {
+		render.RespondSuccess(w, http.StatusAccepted, map[string]string{"status": registrationReceivedMssg})
+		return
+	}
+
 	user, err := queries.CreateUser(r.Context(), database.CreateUserParams{
-		Username:     cu.Username,
-		DisplayName:  cu.DisplayName,
-		Password:     password,
-		PasswordSalt: passwordSalt,
-		CreatedAt:    time.Now().UTC(),
-		UpdatedAt:    time.Now().UTC(),
+		Username:    cu.Username,
+		DisplayName: cu.DisplayName,
+		Email:       pgtype.Text{String: cu.Email, Valid: true},
+		Password:    []byte(password),
+		CreatedAt:   time.Now().UTC(),
+		UpdatedAt:   time.Now().UTC(),
 	})
 	if err != nil {
 		var pgErr *pgconn.PgError
@@ -107,16 +113,14 @@ func handleCreateUser(w http.ResponseWriter, r *http.Request) {
 				"constraint", pgErr.ConstraintName,
 			)
 		}
-		render.RespondFailure(w, http.StatusInsufficientStorage, insufficientStorageErrorMssg)
+		render.RespondSuccess(w, http.StatusAccepted, map[string]string{"status": registrationReceivedMssg})
 		return
 	}
-	// send back user data
-	render.RespondSuccess(w, http.StatusCreated, PublicUserDetails{
-		UserID:      user.UserID,
-		Username:    user.Username,
-		DisplayName: user.DisplayName,
-		CreatedAt:   user.CreatedAt,
-	})
+
+	if err := sendEmailVerification(r, queries, user); err != nil {
+		slog.Error("could not send verification email", "error", err)
+	}
+	render.RespondSuccess(w, http.StatusAccepted, map[string]string{"status": registrationReceivedMssg})
 }
 
 type updateUserData struct {
@@ -151,8 +155,13 @@ func handleUpdateUser(w http.ResponseWriter, r *http.Request) {
 	}
 	// find the updated fields
 	if ud.Password != nil {
-		updatedPassword := auth.SaltyPassword([]byte(*ud.Password), user.PasswordSalt)
-		user.Password = updatedPassword
+		updatedPassword, err := auth.HashPassword([]byte(*ud.Password))
+		if err != nil {
+			slog.Error("error hashing password", "error", err)
+			render.RespondFailure(w, http.StatusInternalServerError, internalServerErrorMssg)
+			return
+		}
+		user.Password = []byte(updatedPassword)
 	}
 	if ud.Username != nil {
 		user.Username = *ud.Username
@@ -188,13 +197,258 @@ func handleDeleteUser(w http.ResponseWriter, r *http.Request) {
 	render.RespondSuccess(w, http.StatusOK, delUser)
 }
 
+type sessionDetails struct {
+	ID        pgtype.UUID `json:"id"`
+	IssuedAt  time.Time   `json:"issued_at"`
+	ExpiresAt time.Time   `json:"expires_at"`
+	UserAgent string      `json:"user_agent,omitempty"`
+	Ip        string      `json:"ip,omitempty"`
+}
+
+func handleListSessions(w http.ResponseWriter, r *http.Request) {
+	user := auth.GetUserData(r)
+	apiCfg := apiconf.GetConfig(r)
+	queries := database.New(apiCfg.ConnPool)
+	sessions, err := queries.ListActiveSessions(r.Context(), database.ListActiveSessionsParams{
+		UserPvtID: user.PvtID,
+		ExpiresAt: time.Now().UTC(),
+	})
+	if err != nil {
+		render.RespondFailure(w, http.StatusInternalServerError, internalServerErrorMssg)
+		return
+	}
+	details := make([]sessionDetails, 0, len(sessions))
+	for _, session := range sessions {
+		details = append(details, sessionDetails{
+			ID:        session.ID,
+			IssuedAt:  session.IssuedAt,
+			ExpiresAt: session.ExpiresAt,
+			UserAgent: session.UserAgent.String,
+			Ip:        session.Ip.String,
+		})
+	}
+	render.RespondSuccess(w, http.StatusOK, details)
+}
+
+func handleDeleteSession(w http.ResponseWriter, r *http.Request) {
+	user := auth.GetUserData(r)
+	var sessionID pgtype.UUID
+	if err := sessionID.Scan(chi.URLParam(r, "id")); err != nil {
+		render.RespondFailure(w, http.StatusBadRequest, "invalid session id")
+		return
+	}
+	apiCfg := apiconf.GetConfig(r)
+	queries := database.New(apiCfg.ConnPool)
+	session, err := queries.GetSessionByID(r.Context(), database.GetSessionByIDParams{
+		ID:        sessionID,
+		UserPvtID: user.PvtID,
+	})
+	if err != nil {
+		render.RespondFailure(w, http.StatusNotFound, "session not found")
+		return
+	}
+	_, err = queries.RevokeRefreshToken(r.Context(), database.RevokeRefreshTokenParams{
+		RevokedAt: pgtype.Timestamp{Time: time.Now().UTC(), Valid: true},
+		ID:        session.ID,
+	})
+	if err != nil {
+		render.RespondFailure(w, http.StatusInternalServerError, internalServerErrorMssg)
+		return
+	}
+	render.RespondSuccess(w, http.StatusOK, map[string]string{"status": "revoked"})
+}
+
+const totpIssuer = "go-chat-api"
+
+type enrollTOTPResponse struct {
+	Secret     string `json:"secret"`
+	OtpauthURI string `json:"otpauth_uri"`
+}
+
+func handleEnrollTOTP(w http.ResponseWriter, r *http.Request) {
+	user := auth.GetUserData(r)
+	secret, otpauthURI, err := auth.GenerateTOTPSecret(totpIssuer, user.Username)
+	if err != nil {
+		slog.Error("could not generate totp secret", "error", err)
+		render.RespondFailure(w, http.StatusInternalServerError, internalServerErrorMssg)
+		return
+	}
+	encrypted, err := auth.EncryptTOTPSecret(secret)
+	if err != nil {
+		slog.Error("could not encrypt totp secret", "error", err)
+		render.RespondFailure(w, http.StatusInternalServerError, internalServerErrorMssg)
+		return
+	}
+	apiCfg := apiconf.GetConfig(r)
+	queries := database.New(apiCfg.ConnPool)
+	err = queries.SetUserTOTPSecret(r.Context(), database.SetUserTOTPSecretParams{
+		TotpSecret: encrypted,
+		PvtID:      user.PvtID,
+	})
+	if err != nil {
+		render.RespondFailure(w, http.StatusInsufficientStorage, "could not start 2fa enrollment")
+		return
+	}
+	render.RespondSuccess(w, http.StatusOK, enrollTOTPResponse{Secret: secret, OtpauthURI: otpauthURI})
+}
+
+type confirmTOTPData struct {
+	Code string `json:"code" validate:"required,len=6,numeric"`
+}
+
+type recoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+const totpRecoveryCodeCount = 8
+
+func handleConfirmTOTP(w http.ResponseWriter, r *http.Request) {
+	cd := confirmTOTPData{}
+	decoder := json.NewDecoder(r.Body)
+	decoder.Decode(&cd)
+
+	apiCfg := apiconf.GetConfig(r)
+	err := apiCfg.Validate.Struct(cd)
+	if err != nil {
+		validationErrors, ok := err.(validator.ValidationErrors)
+		if !ok {
+			slog.Error("error with validator definition", "error", err)
+			render.RespondFailure(w, http.StatusInternalServerError, internalServerErrorMssg)
+		} else {
+			render.RespondValidationFailure(w, validationErrors)
+		}
+		return
+	}
+
+	user := auth.GetUserData(r)
+	queries := database.New(apiCfg.ConnPool)
+	// Re-fetch to pick up the secret handleEnrollTOTP just persisted;
+	// the request-scoped user predates that write.
+	fresh, err := queries.GetUserByPvtID(r.Context(), user.PvtID)
+	if err != nil || len(fresh.TotpSecret) == 0 {
+		render.RespondFailure(w, http.StatusBadRequest, "2fa enrollment has not been started")
+		return
+	}
+	secret, err := auth.DecryptTOTPSecret(fresh.TotpSecret)
+	if err != nil {
+		slog.Error("could not decrypt totp secret", "error", err)
+		render.RespondFailure(w, http.StatusInternalServerError, internalServerErrorMssg)
+		return
+	}
+	ok, err := auth.ValidateTOTPCode(secret, cd.Code)
+	if err != nil {
+		slog.Error("error validating totp code", "error", err)
+		render.RespondFailure(w, http.StatusInternalServerError, internalServerErrorMssg)
+		return
+	}
+	if !ok {
+		render.RespondFailure(w, http.StatusBadRequest, "code is invalid")
+		return
+	}
+	if err := queries.EnableUserTOTP(r.Context(), user.PvtID); err != nil {
+		render.RespondFailure(w, http.StatusInsufficientStorage, "could not enable 2fa")
+		return
+	}
+
+	codes := make([]string, 0, totpRecoveryCodeCount)
+	for i := 0; i < totpRecoveryCodeCount; i++ {
+		code, err := auth.GenerateRecoveryCode()
+		if err != nil {
+			slog.Error("could not generate recovery code", "error", err)
+			render.RespondFailure(w, http.StatusInternalServerError, internalServerErrorMssg)
+			return
+		}
+		hash, err := auth.HashPassword([]byte(code))
+		if err != nil {
+			slog.Error("could not hash recovery code", "error", err)
+			render.RespondFailure(w, http.StatusInternalServerError, internalServerErrorMssg)
+			return
+		}
+		_, err = queries.CreateRecoveryCode(r.Context(), database.CreateRecoveryCodeParams{
+			UserPvtID: user.PvtID,
+			CodeHash:  []byte(hash),
+			CreatedAt: time.Now().UTC(),
+		})
+		if err != nil {
+			render.RespondFailure(w, http.StatusInsufficientStorage, "could not store recovery codes")
+			return
+		}
+		codes = append(codes, code)
+	}
+	render.RespondSuccess(w, http.StatusOK, recoveryCodesResponse{RecoveryCodes: codes})
+}
+
+type disableTOTPData struct {
+	Password string `json:"password" validate:"required"`
+	Code     string `json:"code" validate:"required,min=6,max=8,alphanum"`
+}
+
+func handleDisableTOTP(w http.ResponseWriter, r *http.Request) {
+	dd := disableTOTPData{}
+	decoder := json.NewDecoder(r.Body)
+	decoder.Decode(&dd)
+
+	apiCfg := apiconf.GetConfig(r)
+	err := apiCfg.Validate.Struct(dd)
+	if err != nil {
+		validationErrors, ok := err.(validator.ValidationErrors)
+		if !ok {
+			slog.Error("error with validator definition", "error", err)
+			render.RespondFailure(w, http.StatusInternalServerError, internalServerErrorMssg)
+		} else {
+			render.RespondValidationFailure(w, validationErrors)
+		}
+		return
+	}
+
+	user := auth.GetUserData(r)
+	passwordOK, _, err := auth.VerifyPassword(auth.DefaultHasher(), user.Password, user.PasswordSalt, []byte(dd.Password))
+	if err != nil {
+		slog.Error("error verifying password", "error", err)
+		render.RespondFailure(w, http.StatusInternalServerError, internalServerErrorMssg)
+		return
+	}
+	if !passwordOK {
+		render.RespondFailure(w, http.StatusBadRequest, "password is invalid")
+		return
+	}
+
+	queries := database.New(apiCfg.ConnPool)
+	codeOK, err := verifyTOTPOrRecoveryCode(r, queries, user, dd.Code)
+	if err != nil {
+		slog.Error("error verifying 2fa code", "error", err)
+		render.RespondFailure(w, http.StatusInternalServerError, internalServerErrorMssg)
+		return
+	}
+	if !codeOK {
+		render.RespondFailure(w, http.StatusBadRequest, "code is invalid")
+		return
+	}
+	if err := queries.DisableUserTOTP(r.Context(), user.PvtID); err != nil {
+		render.RespondFailure(w, http.StatusInsufficientStorage, "could not disable 2fa")
+		return
+	}
+	render.RespondSuccess(w, http.StatusOK, map[string]string{"status": "disabled"})
+}
+
+// userIPLimiter caps authenticated account-management requests (password
+// changes, session revocation, 2FA enrollment) at 20 per minute per client
+// IP, stricter than the general /auth/* bucket since these routes can
+// otherwise be used to grind through 2FA or session-revocation attempts.
+var userIPLimiter = ratelimit.NewIPLimiter(20, time.Minute)
+
 func UserRouter() *chi.Mux {
 	router := chi.NewMux()
 
-	router.With(auth.Authentication).Group(func(r chi.Router) {
+	router.With(auth.Authentication, ratelimit.PerIP(userIPLimiter, "user")).Group(func(r chi.Router) {
 		r.Get("/", handleGetUserDetail)
 		r.Patch("/", handleUpdateUser)
 		r.Delete("/", handleDeleteUser)
+		r.Get("/sessions", handleListSessions)
+		r.Delete("/sessions/{id}", handleDeleteSession)
+		r.Post("/2fa/enroll", handleEnrollTOTP)
+		r.Post("/2fa/confirm", handleConfirmTOTP)
+		r.Post("/2fa/disable", handleDisableTOTP)
 	})
 	router.Post("/", handleCreateUser)
 